@@ -0,0 +1,24 @@
+# Общая часть проверок macbat: песочница, в которой вместо команд macOS
+# работают подставные из tests/bin, а ~/.macbat - временный каталог.
+# Так вывод не зависит ни от Mac, ни от дня запуска, ни от настроек
+# пользователя. Перед подключением задается tests_dir - каталог tests
+
+sandbox=$(mktemp -d "${TMPDIR:-/tmp}/macbat-tests.XXXXXX") || exit 1
+trap 'rm -rf "${sandbox}"' EXIT
+mkdir -p "${sandbox}/home/.macbat"
+# macbat вызывает ioreg, system_profiler и pmset по полному пути
+sed -E 's#/usr/s?bin/(system_profiler|pmset|ioreg)#\1#g' "${tests_dir}/../macbat" > "${sandbox}/macbat"
+
+export HOME="${sandbox}/home" TZ=UTC PATH="${tests_dir}/bin:${PATH}" MACBAT_TEST_SENT="${sandbox}/sent"
+# Локаль должна существовать, иначе perl и bash добавляют в вывод
+# предупреждения; на Mac это en_US.UTF-8, в Linux может быть только C.UTF-8
+export LANG=en_US.UTF-8 TERM=xterm-256color
+locale -a 2> /dev/null | grep -qiE '^en_US\.utf-?8$' || LANG=C.UTF-8
+unset LC_ALL LC_CTYPE NO_COLOR MACBAT_CHAOS MACBAT_UNSUPPORTED
+
+# macbat запускается в zsh, как из crontab; где zsh нет - MACBAT_SHELL=bash
+macbat_shell="${MACBAT_SHELL:-zsh}"
+
+macbat(){
+	"${macbat_shell}" "${sandbox}/macbat" "$@"
+}